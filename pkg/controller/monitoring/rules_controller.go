@@ -0,0 +1,78 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package monitoring reconciles the PrometheusRule that carries
+// KubeSphere's own recording rules and alerts, keeping it in sync with
+// the rules package's registry.
+package monitoring
+
+import (
+	"context"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"kubesphere.io/kubesphere/pkg/models/observability/monitoring/rules"
+)
+
+const controllerName = "ks-monitoring-rules-controller"
+
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch;create;update;patch;delete
+
+// RulesReconciler owns a single PrometheusRule, identified by
+// Namespace/Name, and keeps it equal to rules.BuildPrometheusRule via
+// server-side apply. It watches that PrometheusRule so that manual
+// edits or deletions are reverted on the next reconcile.
+type RulesReconciler struct {
+	client.Client
+
+	Namespace string
+	Name      string
+}
+
+func (r *RulesReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if req.Namespace != r.Namespace || req.Name != r.Name {
+		return ctrl.Result{}, nil
+	}
+
+	rule, err := rules.BuildPrometheusRule(r.Namespace, r.Name)
+	if err != nil {
+		klog.Errorf("%s: failed to build PrometheusRule %s/%s: %v", controllerName, r.Namespace, r.Name, err)
+		return ctrl.Result{}, err
+	}
+	rule.TypeMeta = metav1.TypeMeta{
+		APIVersion: monitoringv1.SchemeGroupVersion.String(),
+		Kind:       monitoringv1.PrometheusRuleKind,
+	}
+
+	if err := r.Patch(ctx, rule, client.Apply, client.ForceOwnership, client.FieldOwner(controllerName)); err != nil {
+		klog.Errorf("%s: failed to apply PrometheusRule %s/%s: %v", controllerName, r.Namespace, r.Name, err)
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *RulesReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.Client = mgr.GetClient()
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(controllerName).
+		For(&monitoringv1.PrometheusRule{}).
+		Complete(r)
+}
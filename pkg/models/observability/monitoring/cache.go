@@ -0,0 +1,261 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sync/singleflight"
+	"k8s.io/apimachinery/pkg/util/cache"
+
+	"kubesphere.io/kubesphere/pkg/simple/client/observability/monitoring"
+)
+
+var (
+	cacheHitsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ks_monitoring_cache_hits_total",
+		Help: "Number of MonitoringOperator queries served from the cache, by method.",
+	}, []string{"method"})
+	cacheMissesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ks_monitoring_cache_misses_total",
+		Help: "Number of MonitoringOperator queries that missed the cache and were sent upstream, by method.",
+	}, []string{"method"})
+	cacheInflightDedupTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ks_monitoring_cache_inflight_dedup_total",
+		Help: "Number of MonitoringOperator queries that were coalesced with an identical in-flight query, by method.",
+	}, []string{"method"})
+	cacheRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ks_monitoring_cache_request_duration_seconds",
+		Help:    "Latency of MonitoringOperator queries through the caching decorator, by method and outcome.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "outcome"})
+)
+
+func init() {
+	prometheus.MustRegister(cacheHitsTotal, cacheMissesTotal, cacheInflightDedupTotal, cacheRequestDuration)
+}
+
+// CacheOptions configures the caching decorator installed by WithCaching.
+type CacheOptions struct {
+	// MaxEntries bounds the TTL LRU's size. Defaults to 1024 if <= 0.
+	MaxEntries int
+	// MinTTL/MaxTTL clamp the TTL derived from a query's step, so a very
+	// fine-grained step doesn't defeat caching and a very coarse one
+	// doesn't serve stale data for too long. Default to 1s and 30s.
+	MinTTL time.Duration
+	MaxTTL time.Duration
+}
+
+func (o CacheOptions) withDefaults() CacheOptions {
+	if o.MaxEntries <= 0 {
+		o.MaxEntries = 1024
+	}
+	if o.MinTTL <= 0 {
+		o.MinTTL = time.Second
+	}
+	if o.MaxTTL <= 0 {
+		o.MaxTTL = 30 * time.Second
+	}
+	return o
+}
+
+// instantBucketWidth is the time-bucket width used to key queries that
+// carry a single instant rather than an explicit step (GetMetric,
+// GetMetadata, GetLabelValues, GetMetricLabelSet, GetKubeSphereStats,
+// GetWorkspaceStats). Requests for the "same" instant that land within
+// the same bucket are coalesced and share a cached result, the way a
+// range query's own step does for it.
+const instantBucketWidth = 15 * time.Second
+
+// WithCaching wraps the operator NewMonitoringOperator builds in a
+// cachingMonitoringOperator: concurrent identical queries are coalesced
+// through a singleflight.Group, and results are served from a TTL LRU
+// for repeat queries within their TTL. Off by default - call sites that
+// already rate-limit how often the console re-queries don't need it,
+// and the cache has its own (small) memory and staleness cost.
+func WithCaching(opts CacheOptions) Option {
+	return func(mo *monitoringOperator) {
+		o := opts.withDefaults()
+		mo.cacheOpts = &o
+	}
+}
+
+// cachingMonitoringOperator decorates another MonitoringOperator,
+// coalescing identical concurrent queries into one upstream call and
+// caching their result for a TTL derived from the query's step - the
+// finer the step, the more a dashboard is actively being watched, so
+// the shorter we're willing to serve a stale point for it.
+type cachingMonitoringOperator struct {
+	next MonitoringOperator
+	opts CacheOptions
+
+	group singleflight.Group
+	cache *cache.LRUExpireCache
+}
+
+func newCachingMonitoringOperator(next MonitoringOperator, opts CacheOptions) *cachingMonitoringOperator {
+	return &cachingMonitoringOperator{
+		next:  next,
+		opts:  opts,
+		cache: cache.NewLRUExpireCache(opts.MaxEntries),
+	}
+}
+
+// do looks key up in the cache, falling back to a singleflight-coalesced
+// call to fetch on a miss, and records the hit/miss/dedup/latency
+// metrics for method along the way. fetch's result is cached under key
+// for ttl.
+func (c *cachingMonitoringOperator) do(method, key string, ttl time.Duration, fetch func() interface{}) interface{} {
+	cacheKey := method + "|" + key
+	if v, ok := c.cache.Get(cacheKey); ok {
+		cacheHitsTotal.WithLabelValues(method).Inc()
+		return v
+	}
+
+	start := time.Now()
+	ran := false
+	v, _, shared := c.group.Do(cacheKey, func() (interface{}, error) {
+		ran = true
+		cacheMissesTotal.WithLabelValues(method).Inc()
+		result := fetch()
+		c.cache.Add(cacheKey, result, ttl)
+		return result, nil
+	})
+	outcome := "miss"
+	// shared is also true for the caller whose fetch ran; only the
+	// callers that waited on it were coalesced.
+	if shared && !ran {
+		cacheInflightDedupTotal.WithLabelValues(method).Inc()
+		outcome = "dedup"
+	}
+	cacheRequestDuration.WithLabelValues(method, outcome).Observe(time.Since(start).Seconds())
+	return v
+}
+
+// bucket floors t to a multiple of width, so two calls within the same
+// width-wide window key to the same bucket and can be coalesced/cached
+// together instead of missing each other by a few milliseconds.
+func bucket(t time.Time, width time.Duration) int64 {
+	return t.Unix() / int64(width.Seconds())
+}
+
+// rangeTTL derives a range query's TTL from its step, per CacheOptions.
+func rangeTTL(step time.Duration, opts CacheOptions) time.Duration {
+	ttl := step / 2
+	if ttl < opts.MinTTL {
+		return opts.MinTTL
+	}
+	if ttl > opts.MaxTTL {
+		return opts.MaxTTL
+	}
+	return ttl
+}
+
+// instantTTL derives an instant query's TTL from instantBucketWidth, per
+// CacheOptions.
+func instantTTL(opts CacheOptions) time.Duration {
+	if instantBucketWidth < opts.MaxTTL {
+		return instantBucketWidth
+	}
+	return opts.MaxTTL
+}
+
+func (c *cachingMonitoringOperator) GetMetric(expr, namespace string, t time.Time) (monitoring.Metric, error) {
+	key := fmt.Sprintf("%s|%s|%d", expr, namespace, bucket(t, instantBucketWidth))
+	v := c.do("GetMetric", key, instantTTL(c.opts), func() interface{} {
+		m, err := c.next.GetMetric(expr, namespace, t)
+		return metricOrErr{metric: m, err: err}
+	})
+	res := v.(metricOrErr)
+	return res.metric, res.err
+}
+
+func (c *cachingMonitoringOperator) GetMetricOverTime(expr, namespace string, start, end time.Time, step time.Duration) (monitoring.Metric, error) {
+	key := fmt.Sprintf("%s|%s|%d|%d|%s", expr, namespace, bucket(start, step), bucket(end, step), step)
+	v := c.do("GetMetricOverTime", key, rangeTTL(step, c.opts), func() interface{} {
+		m, err := c.next.GetMetricOverTime(expr, namespace, start, end, step)
+		return metricOrErr{metric: m, err: err}
+	})
+	res := v.(metricOrErr)
+	return res.metric, res.err
+}
+
+func (c *cachingMonitoringOperator) GetNamedMetrics(metrics []string, t time.Time, opt monitoring.QueryOption, datasource string) Metrics {
+	key := fmt.Sprintf("%v|%+v|%s|%d", metrics, opt, datasource, bucket(t, instantBucketWidth))
+	v := c.do("GetNamedMetrics", key, instantTTL(c.opts), func() interface{} {
+		return c.next.GetNamedMetrics(metrics, t, opt, datasource)
+	})
+	return v.(Metrics)
+}
+
+func (c *cachingMonitoringOperator) GetNamedMetricsOverTime(metrics []string, start, end time.Time, step time.Duration, opt monitoring.QueryOption, datasource string) Metrics {
+	key := fmt.Sprintf("%v|%+v|%s|%d|%d|%s", metrics, opt, datasource, bucket(start, step), bucket(end, step), step)
+	v := c.do("GetNamedMetricsOverTime", key, rangeTTL(step, c.opts), func() interface{} {
+		return c.next.GetNamedMetricsOverTime(metrics, start, end, step, opt, datasource)
+	})
+	return v.(Metrics)
+}
+
+func (c *cachingMonitoringOperator) GetMetadata(namespace string) Metadata {
+	key := fmt.Sprintf("%s|%d", namespace, bucket(time.Now(), instantBucketWidth))
+	v := c.do("GetMetadata", key, instantTTL(c.opts), func() interface{} {
+		return c.next.GetMetadata(namespace)
+	})
+	return v.(Metadata)
+}
+
+func (c *cachingMonitoringOperator) GetLabelValues(label string, matches []string, start, end time.Time) LabelValues {
+	key := fmt.Sprintf("%s|%v|%d|%d", label, matches, bucket(start, instantBucketWidth), bucket(end, instantBucketWidth))
+	v := c.do("GetLabelValues", key, instantTTL(c.opts), func() interface{} {
+		return c.next.GetLabelValues(label, matches, start, end)
+	})
+	return v.(LabelValues)
+}
+
+func (c *cachingMonitoringOperator) GetMetricLabelSet(metric, namespace string, start, end time.Time) MetricLabelSet {
+	key := fmt.Sprintf("%s|%s|%d|%d", metric, namespace, bucket(start, instantBucketWidth), bucket(end, instantBucketWidth))
+	v := c.do("GetMetricLabelSet", key, instantTTL(c.opts), func() interface{} {
+		return c.next.GetMetricLabelSet(metric, namespace, start, end)
+	})
+	return v.(MetricLabelSet)
+}
+
+func (c *cachingMonitoringOperator) GetKubeSphereStats() Metrics {
+	key := fmt.Sprintf("%d", bucket(time.Now(), instantBucketWidth))
+	v := c.do("GetKubeSphereStats", key, instantTTL(c.opts), func() interface{} {
+		return c.next.GetKubeSphereStats()
+	})
+	return v.(Metrics)
+}
+
+func (c *cachingMonitoringOperator) GetWorkspaceStats(workspace string) Metrics {
+	key := fmt.Sprintf("%s|%d", workspace, bucket(time.Now(), instantBucketWidth))
+	v := c.do("GetWorkspaceStats", key, instantTTL(c.opts), func() interface{} {
+		return c.next.GetWorkspaceStats(workspace)
+	})
+	return v.(Metrics)
+}
+
+// metricOrErr lets GetMetric/GetMetricOverTime, whose signature returns
+// an error alongside their result, share the same cache/singleflight
+// plumbing as every other method.
+type metricOrErr struct {
+	metric monitoring.Metric
+	err    error
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"kubesphere.io/kubesphere/pkg/simple/client/observability/monitoring"
+)
+
+// countingOperator wraps a MonitoringOperator and counts calls to
+// GetKubeSphereStats, blocking on release until every caller in a test
+// has had a chance to arrive so concurrent calls actually overlap.
+type countingOperator struct {
+	MonitoringOperator
+	calls   int32
+	release chan struct{}
+}
+
+func (c *countingOperator) GetKubeSphereStats() Metrics {
+	atomic.AddInt32(&c.calls, 1)
+	if c.release != nil {
+		<-c.release
+	}
+	return Metrics{Results: []monitoring.Metric{{MetricName: "kubesphere_cluster_count"}}}
+}
+
+func TestCachingMonitoringOperatorCoalescesConcurrentQueries(t *testing.T) {
+	release := make(chan struct{})
+	inner := &countingOperator{release: release}
+	c := newCachingMonitoringOperator(inner, CacheOptions{}.withDefaults())
+
+	const callers = 10
+	var wg sync.WaitGroup
+	wg.Add(callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			defer wg.Done()
+			c.GetKubeSphereStats()
+		}()
+	}
+
+	// Give every goroutine a chance to reach the singleflight call
+	// before letting the one in-flight upstream call return.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&inner.calls); got != 1 {
+		t.Errorf("expected exactly 1 upstream call for %d concurrent identical queries, got %d", callers, got)
+	}
+}
+
+func TestCachingMonitoringOperatorServesFromCacheWithinTTL(t *testing.T) {
+	inner := &countingOperator{}
+	c := newCachingMonitoringOperator(inner, CacheOptions{}.withDefaults())
+
+	c.GetKubeSphereStats()
+	c.GetKubeSphereStats()
+
+	if got := atomic.LoadInt32(&inner.calls); got != 1 {
+		t.Errorf("expected the second call to be served from cache, got %d upstream calls", got)
+	}
+}
+
+func TestRangeTTLDerivedFromStepAndClamped(t *testing.T) {
+	opts := CacheOptions{}.withDefaults()
+
+	if got := rangeTTL(2*time.Second, opts); got != opts.MinTTL {
+		t.Errorf("expected a tiny step to clamp to MinTTL %s, got %s", opts.MinTTL, got)
+	}
+	if got := rangeTTL(time.Hour, opts); got != opts.MaxTTL {
+		t.Errorf("expected a huge step to clamp to MaxTTL %s, got %s", opts.MaxTTL, got)
+	}
+	if got, want := rangeTTL(10*time.Second, opts), 5*time.Second; got != want {
+		t.Errorf("rangeTTL(10s) = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import "kubesphere.io/kubesphere/pkg/simple/client/observability/monitoring"
+
+// Valid values for the ?datasource= query parameter accepted by the
+// named-metrics endpoints.
+const (
+	DataSourcePrometheus    = "prometheus"
+	DataSourceMetricsServer = "metrics-server"
+)
+
+// metricsServerUnavailable reports an error result for every requested
+// metric name, for callers that explicitly asked for the metrics-server
+// datasource on an operator that was never given one via
+// WithMetricsServerClient.
+func metricsServerUnavailable(metrics []string) []monitoring.Metric {
+	results := make([]monitoring.Metric, 0, len(metrics))
+	for _, name := range metrics {
+		results = append(results, monitoring.Metric{
+			MetricName: name,
+			Error:      "metrics-server datasource requested but not configured",
+			Source:     DataSourceMetricsServer,
+		})
+	}
+	return results
+}
+
+// tagSource stamps every result with the backend that produced it, so
+// API responses stay debuggable once more than one backend is in play.
+func tagSource(metrics []monitoring.Metric, source string) []monitoring.Metric {
+	for i := range metrics {
+		metrics[i].Source = source
+	}
+	return metrics
+}
+
+// missingMetricNames returns the names in requested whose primary-backend
+// result was empty or errored, i.e. the ones worth retrying against the
+// metrics-server fallback.
+func missingMetricNames(requested []string, results []monitoring.Metric) []string {
+	answered := make(map[string]bool, len(results))
+	for _, r := range results {
+		if r.Error == "" && len(r.MetricValues) > 0 {
+			answered[r.MetricName] = true
+		}
+	}
+
+	var missing []string
+	for _, name := range requested {
+		if !answered[name] {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// mergeMetrics replaces any empty/errored entry in primary with the
+// matching entry from fallback, if the fallback backend answered it.
+func mergeMetrics(primary, fallback []monitoring.Metric) []monitoring.Metric {
+	byName := make(map[string]monitoring.Metric, len(fallback))
+	for _, m := range fallback {
+		byName[m.MetricName] = m
+	}
+
+	merged := make([]monitoring.Metric, len(primary))
+	for i, m := range primary {
+		if m.Error == "" && len(m.MetricValues) > 0 {
+			merged[i] = m
+			continue
+		}
+		if replacement, ok := byName[m.MetricName]; ok {
+			merged[i] = replacement
+			continue
+		}
+		merged[i] = m
+	}
+	return merged
+}
@@ -0,0 +1,31 @@
+/*
+Copyright 2019 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package expressions rewrites a user-supplied PromQL expression so it
+// can only ever see series belonging to one namespace. Every monitoring
+// backend has a different way of achieving that; each implementation
+// registers itself into ReplaceNamespaceFns under its own
+// monitoring.Interface.Name(), keyed by backend rather than hard-coded
+// to Prometheus.
+package expressions
+
+// ReplaceNamespaceFn rewrites expr so it only returns series scoped to
+// namespace.
+type ReplaceNamespaceFn func(expr, namespace string) (string, error)
+
+// ReplaceNamespaceFns is keyed by the owning monitoring.Interface's
+// Name(). Backend packages populate it from their own init().
+var ReplaceNamespaceFns = map[string]ReplaceNamespaceFn{}
@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expressions
+
+import (
+	"fmt"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"kubesphere.io/kubesphere/pkg/simple/client/observability/monitoring"
+)
+
+// injectNamespaceMatcher walks expr and adds a namespace="..." matcher
+// to every vector selector it finds - range-vector selectors (rate(x[5m]))
+// are covered too, since their underlying vector selector is a child node
+// the walk already visits. The rewritten expression can only ever return
+// series from that one namespace. Prometheus and Thanos both speak PromQL,
+// so they share this implementation; a Thanos query additionally carries
+// partial-response and tenant query params, which is handled at the HTTP
+// layer, not here.
+func injectNamespaceMatcher(expr, namespace string) (string, error) {
+	e, err := parser.ParseExpr(expr)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse expression %q: %v", expr, err)
+	}
+
+	namespaceMatcher, err := labels.NewMatcher(labels.MatchEqual, "namespace", namespace)
+	if err != nil {
+		return "", err
+	}
+
+	parser.Inspect(e, func(node parser.Node, path []parser.Node) error {
+		// parser.Inspect/Walk already recurses into a MatrixSelector's
+		// own VectorSelector child, so matching on *parser.VectorSelector
+		// alone is enough to cover both instant and range-vector
+		// selectors - a separate MatrixSelector case would visit that
+		// same child a second time and double up its matchers.
+		if vs, ok := node.(*parser.VectorSelector); ok {
+			vs.LabelMatchers = append(vs.LabelMatchers, namespaceMatcher)
+		}
+		return nil
+	})
+
+	return e.String(), nil
+}
+
+func init() {
+	// Prometheus, Thanos, VictoriaMetrics and Mimir/Cortex all accept
+	// plain PromQL, so ad hoc expressions (GetMetric/GetMetricLabelSet)
+	// are namespace-scoped the same way for all four. VictoriaMetrics
+	// additionally sets its native extra_label parameter on the
+	// structured named-metric query path, where a QueryOption carries the
+	// namespace; Mimir sends one fixed X-Scope-OrgID tenant header on
+	// every request - see their respective client packages.
+	ReplaceNamespaceFns[monitoring.BackendPrometheus] = injectNamespaceMatcher
+	ReplaceNamespaceFns[monitoring.BackendThanos] = injectNamespaceMatcher
+	ReplaceNamespaceFns[monitoring.BackendVictoriaMetrics] = injectNamespaceMatcher
+	ReplaceNamespaceFns[monitoring.BackendMimir] = injectNamespaceMatcher
+}
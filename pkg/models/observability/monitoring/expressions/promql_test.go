@@ -0,0 +1,61 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expressions
+
+import (
+	"strings"
+	"testing"
+
+	"kubesphere.io/kubesphere/pkg/simple/client/observability/monitoring"
+)
+
+func TestInjectNamespaceMatcherInstantVector(t *testing.T) {
+	got, err := injectNamespaceMatcher(`up{job="foo"}`, "demo-ns")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `up{job="foo", namespace="demo-ns"}`; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestInjectNamespaceMatcherRangeVector(t *testing.T) {
+	got, err := injectNamespaceMatcher(`rate(foo[5m])`, "demo-ns")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `rate(foo{namespace="demo-ns"}[5m])`; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if n := strings.Count(got, "namespace="); n != 1 {
+		t.Fatalf("expected namespace matcher to be injected exactly once, got %d in %q", n, got)
+	}
+}
+
+func TestReplaceNamespaceFnsRegistration(t *testing.T) {
+	for _, backend := range []string{
+		monitoring.BackendPrometheus,
+		monitoring.BackendThanos,
+		monitoring.BackendVictoriaMetrics,
+		monitoring.BackendMimir,
+	} {
+		fn, ok := ReplaceNamespaceFns[backend]
+		if !ok || fn == nil {
+			t.Fatalf("expected ReplaceNamespaceFns to have an entry for backend %q", backend)
+		}
+	}
+}
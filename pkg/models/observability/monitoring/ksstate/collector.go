@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ksstate
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog"
+
+	ksinformers "kubesphere.io/kubesphere/pkg/client/informers/externalversions"
+	"kubesphere.io/kubesphere/pkg/informers"
+)
+
+// collector implements prometheus.Collector on top of the families
+// registered in families.go, filtered down to the ones allowed by opts.
+type collector struct {
+	k8s        kubernetes.Interface
+	ks         ksinformers.SharedInformerFactory
+	namespaces corev1listers.NamespaceLister
+	families   []family
+	descs      []*prometheus.Desc
+}
+
+// NewCollector builds the prometheus.Collector that backs the /ks-metrics
+// endpoint. Values are always read straight from factory's shared
+// informer caches at scrape time; NewCollector itself does not list
+// anything and does not start a ticker.
+func NewCollector(k8s kubernetes.Interface, factory informers.InformerFactory, opts *Options) prometheus.Collector {
+	filtered := filterFamilies(families, opts)
+	descs := make([]*prometheus.Desc, 0, len(filtered))
+	for _, f := range filtered {
+		descs = append(descs, prometheus.NewDesc(f.name, f.help, f.labelNames, nil))
+	}
+	return &collector{
+		k8s:        k8s,
+		ks:         factory.KubeSphereSharedInformerFactory(),
+		namespaces: factory.KubernetesSharedInformerFactory().Core().V1().Namespaces().Lister(),
+		families:   filtered,
+		descs:      descs,
+	}
+}
+
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range c.descs {
+		ch <- d
+	}
+}
+
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	for i, f := range c.families {
+		values, err := f.generate(c.ks, c.k8s, c.namespaces)
+		if err != nil {
+			klog.Errorf("ksstate: failed to collect %s: %v", f.name, err)
+			continue
+		}
+		for _, v := range values {
+			m, err := prometheus.NewConstMetric(c.descs[i], f.valueType, v.value, v.labelValues...)
+			if err != nil {
+				klog.Errorf("ksstate: failed to build metric %s: %v", f.name, err)
+				continue
+			}
+			ch <- m
+		}
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ksstate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sinformers "k8s.io/client-go/informers"
+	corefake "k8s.io/client-go/kubernetes/fake"
+
+	clusterv1alpha1 "kubesphere.io/api/cluster/v1alpha1"
+	iamv1alpha2 "kubesphere.io/api/iam/v1alpha2"
+	tenantv1alpha2 "kubesphere.io/api/tenant/v1alpha2"
+
+	ksfake "kubesphere.io/kubesphere/pkg/client/clientset/versioned/fake"
+	ksinformers "kubesphere.io/kubesphere/pkg/client/informers/externalversions"
+	"kubesphere.io/kubesphere/pkg/constants"
+	"kubesphere.io/kubesphere/pkg/informers"
+)
+
+// testFactory overrides KubeSphereSharedInformerFactory and
+// KubernetesSharedInformerFactory on top of a nil informers.InformerFactory;
+// the collector never calls any other method.
+type testFactory struct {
+	informers.InformerFactory
+	ks   ksinformers.SharedInformerFactory
+	core k8sinformers.SharedInformerFactory
+}
+
+func (t testFactory) KubeSphereSharedInformerFactory() ksinformers.SharedInformerFactory {
+	return t.ks
+}
+
+func (t testFactory) KubernetesSharedInformerFactory() k8sinformers.SharedInformerFactory {
+	return t.core
+}
+
+func TestCollector(t *testing.T) {
+	k8sClient := corefake.NewSimpleClientset()
+
+	ksClient := ksfake.NewSimpleClientset(
+		&clusterv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "host"}},
+		&tenantv1alpha2.WorkspaceTemplate{ObjectMeta: metav1.ObjectMeta{Name: "demo-ws"}},
+		&iamv1alpha2.User{ObjectMeta: metav1.ObjectMeta{Name: "admin"}},
+		&iamv1alpha2.WorkspaceRole{ObjectMeta: metav1.ObjectMeta{Name: "demo-ws-admin", Labels: map[string]string{constants.WorkspaceLabelKey: "demo-ws"}}},
+		&iamv1alpha2.WorkspaceRoleBinding{ObjectMeta: metav1.ObjectMeta{
+			Name:   "demo-ws-admin-binding",
+			Labels: map[string]string{constants.WorkspaceLabelKey: "demo-ws", iamv1alpha2.UserReferenceLabel: "admin"},
+		}},
+	)
+
+	ksInformers := ksinformers.NewSharedInformerFactory(ksClient, 0)
+	coreInformers := k8sinformers.NewSharedInformerFactory(k8sClient, 0)
+	factory := testFactory{ks: ksInformers, core: coreInformers}
+
+	// Informers are created lazily and Start only runs the ones requested
+	// so far, so request the ones the asserted families list from first.
+	ksInformers.Cluster().V1alpha1().Clusters().Informer()
+	ksInformers.Tenant().V1alpha2().WorkspaceTemplates().Informer()
+	ksInformers.Iam().V1alpha2().Users().Informer()
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	ksInformers.Start(stopCh)
+	ksInformers.WaitForCacheSync(stopCh)
+	coreInformers.Start(stopCh)
+	coreInformers.WaitForCacheSync(stopCh)
+
+	collector := NewCollector(k8sClient, factory, NewOptions())
+
+	expected := strings.NewReader(`
+# HELP kubesphere_cluster_count Number of clusters known to this KubeSphere control plane.
+# TYPE kubesphere_cluster_count gauge
+kubesphere_cluster_count 1
+# HELP kubesphere_user_count Number of users known to this KubeSphere control plane.
+# TYPE kubesphere_user_count gauge
+kubesphere_user_count 1
+# HELP kubesphere_workspace_count Number of workspaces known to this KubeSphere control plane.
+# TYPE kubesphere_workspace_count gauge
+kubesphere_workspace_count 1
+`)
+
+	if err := testutil.CollectAndCompare(collector, expected,
+		"kubesphere_cluster_count", "kubesphere_user_count", "kubesphere_workspace_count"); err != nil {
+		t.Fatalf("unexpected collecting result:\n%s", err)
+	}
+}
+
+func TestFilterFamilies(t *testing.T) {
+	allowed := filterFamilies(families, &Options{MetricAllowlist: []string{"kubesphere_cluster_count"}})
+	if len(allowed) != 1 || allowed[0].name != "kubesphere_cluster_count" {
+		t.Fatalf("allowlist did not restrict families, got %v", allowed)
+	}
+
+	denied := filterFamilies(families, &Options{MetricDenylist: []string{"kubesphere_cluster_count"}})
+	for _, f := range denied {
+		if f.name == "kubesphere_cluster_count" {
+			t.Fatalf("denylist did not remove kubesphere_cluster_count")
+		}
+	}
+	if len(denied) != len(families)-1 {
+		t.Fatalf("expected denylist to drop exactly one family, got %d of %d", len(denied), len(families))
+	}
+}
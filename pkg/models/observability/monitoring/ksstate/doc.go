@@ -0,0 +1,29 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ksstate exposes KubeSphere's own object counts (clusters,
+// workspaces, users, namespaces, DevOps projects, workspace roles and
+// members) as Prometheus metrics, following the same conventions as
+// kube-state-metrics: every family is described once up front and its
+// value is computed lazily, on scrape, straight from the shared informer
+// caches that are already kept warm for the rest of the API server. No
+// background polling loop is started by this package.
+//
+// This package only builds the collector and the http.Handler (see
+// Handler); actually mounting that handler on ks-apiserver's /ks-metrics
+// route is done alongside every other handler in ks-apiserver's route
+// table, which is out of this package's scope.
+package ksstate
@@ -0,0 +1,182 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ksstate
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	"kubesphere.io/api/iam/v1alpha2"
+
+	ksinformers "kubesphere.io/kubesphere/pkg/client/informers/externalversions"
+	"kubesphere.io/kubesphere/pkg/constants"
+)
+
+// metricValue is a single exposition-time sample: the label values that
+// fill in a family's declared labelNames, paired with the counted value.
+type metricValue struct {
+	labelValues []string
+	value       float64
+}
+
+// family declares one metric exactly once (name, help text, type and
+// label schema) and knows how to compute its current value(s) on demand.
+// It never caches a result across scrapes.
+type family struct {
+	name       string
+	help       string
+	valueType  prometheus.ValueType
+	labelNames []string
+	generate   func(ks ksinformers.SharedInformerFactory, k8s kubernetes.Interface, namespaces corev1listers.NamespaceLister) ([]metricValue, error)
+}
+
+// families is the registry of every metric this package knows how to
+// expose. Allow/deny filtering (see options.go) operates on family.name,
+// so adding a new family here is all that's needed to ship a new metric.
+var families = []family{
+	{
+		name:      "kubesphere_cluster_count",
+		help:      "Number of clusters known to this KubeSphere control plane.",
+		valueType: prometheus.GaugeValue,
+		generate: func(ks ksinformers.SharedInformerFactory, _ kubernetes.Interface, _ corev1listers.NamespaceLister) ([]metricValue, error) {
+			clusters, err := ks.Cluster().V1alpha1().Clusters().Lister().List(labels.Everything())
+			if err != nil {
+				return nil, err
+			}
+			return []metricValue{{value: float64(len(clusters))}}, nil
+		},
+	},
+	{
+		name:      "kubesphere_workspace_count",
+		help:      "Number of workspaces known to this KubeSphere control plane.",
+		valueType: prometheus.GaugeValue,
+		generate: func(ks ksinformers.SharedInformerFactory, _ kubernetes.Interface, _ corev1listers.NamespaceLister) ([]metricValue, error) {
+			workspaces, err := ks.Tenant().V1alpha2().WorkspaceTemplates().Lister().List(labels.Everything())
+			if err != nil {
+				return nil, err
+			}
+			return []metricValue{{value: float64(len(workspaces))}}, nil
+		},
+	},
+	{
+		name:      "kubesphere_user_count",
+		help:      "Number of users known to this KubeSphere control plane.",
+		valueType: prometheus.GaugeValue,
+		generate: func(ks ksinformers.SharedInformerFactory, _ kubernetes.Interface, _ corev1listers.NamespaceLister) ([]metricValue, error) {
+			users, err := ks.Iam().V1alpha2().Users().Lister().List(labels.Everything())
+			if err != nil {
+				return nil, err
+			}
+			return []metricValue{{value: float64(len(users))}}, nil
+		},
+	},
+	{
+		name:       "kubesphere_workspace_namespace_count",
+		help:       "Number of namespaces per workspace.",
+		valueType:  prometheus.GaugeValue,
+		labelNames: []string{"workspace"},
+		generate: func(ks ksinformers.SharedInformerFactory, _ kubernetes.Interface, namespaces corev1listers.NamespaceLister) ([]metricValue, error) {
+			workspaces, err := ks.Tenant().V1alpha2().WorkspaceTemplates().Lister().List(labels.Everything())
+			if err != nil {
+				return nil, err
+			}
+			values := make([]metricValue, 0, len(workspaces))
+			for _, ws := range workspaces {
+				selector := labels.SelectorFromSet(labels.Set{constants.WorkspaceLabelKey: ws.Name})
+				nsList, err := namespaces.List(selector)
+				if err != nil {
+					return nil, err
+				}
+				values = append(values, metricValue{labelValues: []string{ws.Name}, value: float64(len(nsList))})
+			}
+			return values, nil
+		},
+	},
+	{
+		name:       "kubesphere_workspace_devops_project_count",
+		help:       "Number of DevOps projects per workspace.",
+		valueType:  prometheus.GaugeValue,
+		labelNames: []string{"workspace"},
+		generate: func(ks ksinformers.SharedInformerFactory, _ kubernetes.Interface, _ corev1listers.NamespaceLister) ([]metricValue, error) {
+			workspaces, err := ks.Tenant().V1alpha2().WorkspaceTemplates().Lister().List(labels.Everything())
+			if err != nil {
+				return nil, err
+			}
+			values := make([]metricValue, 0, len(workspaces))
+			for _, ws := range workspaces {
+				selector := labels.SelectorFromSet(labels.Set{constants.WorkspaceLabelKey: ws.Name})
+				devopsList, err := ks.Devops().V1alpha3().DevOpsProjects().Lister().List(selector)
+				if err != nil {
+					return nil, err
+				}
+				values = append(values, metricValue{labelValues: []string{ws.Name}, value: float64(len(devopsList))})
+			}
+			return values, nil
+		},
+	},
+	{
+		name:       "kubesphere_workspace_role_count",
+		help:       "Number of workspace roles per workspace.",
+		valueType:  prometheus.GaugeValue,
+		labelNames: []string{"workspace"},
+		generate: func(ks ksinformers.SharedInformerFactory, _ kubernetes.Interface, _ corev1listers.NamespaceLister) ([]metricValue, error) {
+			workspaces, err := ks.Tenant().V1alpha2().WorkspaceTemplates().Lister().List(labels.Everything())
+			if err != nil {
+				return nil, err
+			}
+			values := make([]metricValue, 0, len(workspaces))
+			for _, ws := range workspaces {
+				selector := labels.SelectorFromSet(labels.Set{constants.WorkspaceLabelKey: ws.Name})
+				roleList, err := ks.Iam().V1alpha2().WorkspaceRoles().Lister().List(selector)
+				if err != nil {
+					return nil, err
+				}
+				values = append(values, metricValue{labelValues: []string{ws.Name}, value: float64(len(roleList))})
+			}
+			return values, nil
+		},
+	},
+	{
+		name:       "kubesphere_workspace_member_count",
+		help:       "Number of members per workspace.",
+		valueType:  prometheus.GaugeValue,
+		labelNames: []string{"workspace"},
+		generate: func(ks ksinformers.SharedInformerFactory, _ kubernetes.Interface, _ corev1listers.NamespaceLister) ([]metricValue, error) {
+			workspaces, err := ks.Tenant().V1alpha2().WorkspaceTemplates().Lister().List(labels.Everything())
+			if err != nil {
+				return nil, err
+			}
+			r, err := labels.NewRequirement(v1alpha2.UserReferenceLabel, selection.Exists, nil)
+			if err != nil {
+				return nil, err
+			}
+			values := make([]metricValue, 0, len(workspaces))
+			for _, ws := range workspaces {
+				selector := labels.SelectorFromSet(labels.Set{constants.WorkspaceLabelKey: ws.Name}).DeepCopySelector().Add(*r)
+				memberList, err := ks.Iam().V1alpha2().WorkspaceRoleBindings().Lister().List(selector)
+				if err != nil {
+					return nil, err
+				}
+				values = append(values, metricValue{labelValues: []string{ws.Name}, value: float64(len(memberList))})
+			}
+			return values, nil
+		},
+	},
+}
@@ -0,0 +1,39 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ksstate
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/client-go/kubernetes"
+
+	"kubesphere.io/kubesphere/pkg/informers"
+)
+
+// Handler builds the http.Handler that ks-apiserver is expected to mount
+// on /ks-metrics - this package stops at handing back the http.Handler;
+// registering it on that path is done wherever ks-apiserver builds its
+// route table (not part of this tree), the same way it mounts any other
+// package's handler. It owns a dedicated registry so KubeSphere self
+// metrics stay isolated from the process's default Go-runtime registry.
+func Handler(k8s kubernetes.Interface, factory informers.InformerFactory, opts *Options) http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewCollector(k8s, factory, opts))
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{ErrorHandling: promhttp.ContinueOnError})
+}
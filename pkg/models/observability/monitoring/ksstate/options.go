@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ksstate
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+// Options restricts which families NewCollector exposes. At most one of
+// MetricAllowlist / MetricDenylist may be non-empty, mirroring
+// kube-state-metrics' --metric-allowlist/--metric-denylist flags.
+type Options struct {
+	MetricAllowlist []string
+	MetricDenylist  []string
+}
+
+// NewOptions returns an Options with no restrictions, i.e. every family
+// in families is exposed.
+func NewOptions() *Options {
+	return &Options{}
+}
+
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringSliceVar(&o.MetricAllowlist, "ks-metrics-allowlist", o.MetricAllowlist,
+		"Comma-separated list of metric names to expose on /ks-metrics. This list comprises of exact metric names "+
+			"and is mutually exclusive with ks-metrics-denylist.")
+	fs.StringSliceVar(&o.MetricDenylist, "ks-metrics-denylist", o.MetricDenylist,
+		"Comma-separated list of metric names not to expose on /ks-metrics. This list comprises of exact metric names "+
+			"and is mutually exclusive with ks-metrics-allowlist.")
+}
+
+func (o *Options) Validate() []error {
+	if len(o.MetricAllowlist) > 0 && len(o.MetricDenylist) > 0 {
+		return []error{fmt.Errorf("ks-metrics-allowlist and ks-metrics-denylist are mutually exclusive")}
+	}
+	return nil
+}
+
+func filterFamilies(all []family, opts *Options) []family {
+	if opts == nil || (len(opts.MetricAllowlist) == 0 && len(opts.MetricDenylist) == 0) {
+		return all
+	}
+
+	if len(opts.MetricAllowlist) > 0 {
+		allow := toSet(opts.MetricAllowlist)
+		filtered := make([]family, 0, len(all))
+		for _, f := range all {
+			if allow[f.name] {
+				filtered = append(filtered, f)
+			}
+		}
+		return filtered
+	}
+
+	deny := toSet(opts.MetricDenylist)
+	filtered := make([]family, 0, len(all))
+	for _, f := range all {
+		if !deny[f.name] {
+			filtered = append(filtered, f)
+		}
+	}
+	return filtered
+}
+
+func toSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, n := range names {
+		set[n] = true
+	}
+	return set
+}
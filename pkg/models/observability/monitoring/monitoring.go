@@ -38,59 +38,142 @@ import (
 type MonitoringOperator interface {
 	GetMetric(expr, namespace string, time time.Time) (monitoring.Metric, error)
 	GetMetricOverTime(expr, namespace string, start, end time.Time, step time.Duration) (monitoring.Metric, error)
-	GetNamedMetrics(metrics []string, time time.Time, opt monitoring.QueryOption) Metrics
-	GetNamedMetricsOverTime(metrics []string, start, end time.Time, step time.Duration, opt monitoring.QueryOption) Metrics
+	// datasource optionally forces the query to a specific backend:
+	// "metrics-server", or the primary backend's own Name() (e.g.
+	// "prometheus"). Any other value, including an empty one, queries the
+	// primary backend and transparently falls back to the metrics-server
+	// client, if one is configured, for the metric names it supports.
+	GetNamedMetrics(metrics []string, time time.Time, opt monitoring.QueryOption, datasource string) Metrics
+	GetNamedMetricsOverTime(metrics []string, start, end time.Time, step time.Duration, opt monitoring.QueryOption, datasource string) Metrics
 	GetMetadata(namespace string) Metadata
 	GetLabelValues(label string, matches []string, start, end time.Time) LabelValues
 	GetMetricLabelSet(metric, namespace string, start, end time.Time) MetricLabelSet
 
-	// TODO: expose KubeSphere self metrics in Prometheus format
+	// GetKubeSphereStats and GetWorkspaceStats back the legacy JSON stats
+	// API. The ksstate subpackage exposes the same counts in Prometheus
+	// exposition format, except that it reports the cluster count as
+	// listed, while GetKubeSphereStats reports at least 1.
 	GetKubeSphereStats() Metrics
 	GetWorkspaceStats(workspace string) Metrics
 }
 
 type monitoringOperator struct {
-	prometheus monitoring.Interface
-	k8s        kubernetes.Interface
-	ks         ksinformers.SharedInformerFactory
+	// primary is whichever monitoring.Interface the deployment was
+	// configured with, identified by primary.Name(). It is not
+	// necessarily Prometheus - see the monitoring options' backend flag.
+	primary       monitoring.Interface
+	metricsServer monitoring.Interface // optional, nil unless WithMetricsServerClient is passed
+	k8s           kubernetes.Interface
+	ks            ksinformers.SharedInformerFactory
+	cacheOpts     *CacheOptions // optional, nil unless WithCaching is passed
 }
 
-func NewMonitoringOperator(monitoringClient monitoring.Interface, k8s kubernetes.Interface, factory informers.InformerFactory) MonitoringOperator {
-	return &monitoringOperator{
-		prometheus: monitoringClient,
-		k8s:        k8s,
-		ks:         factory.KubeSphereSharedInformerFactory(),
+// Option configures optional behavior of a monitoringOperator built by
+// NewMonitoringOperator.
+type Option func(*monitoringOperator)
+
+// WithMetricsServerClient registers a metrics.k8s.io backed
+// monitoring.Interface that GetNamedMetrics/GetNamedMetricsOverTime fall
+// back to for the node/pod resource metrics it supports, whenever the
+// primary backend's query comes back empty or errored.
+func WithMetricsServerClient(client monitoring.Interface) Option {
+	return func(mo *monitoringOperator) {
+		mo.metricsServer = client
 	}
 }
 
+func NewMonitoringOperator(monitoringClient monitoring.Interface, k8s kubernetes.Interface, factory informers.InformerFactory, opts ...Option) MonitoringOperator {
+	mo := &monitoringOperator{
+		primary: monitoringClient,
+		k8s:     k8s,
+		ks:      factory.KubeSphereSharedInformerFactory(),
+	}
+	for _, opt := range opts {
+		opt(mo)
+	}
+	if mo.cacheOpts != nil {
+		return newCachingMonitoringOperator(mo, *mo.cacheOpts)
+	}
+	return mo
+}
+
 func (mo monitoringOperator) GetMetric(expr, namespace string, time time.Time) (monitoring.Metric, error) {
-	return mo.prometheus.GetMetric(expr, time), nil
+	if namespace != "" {
+		replaceNamespace, ok := expressions.ReplaceNamespaceFns[mo.primary.Name()]
+		if !ok {
+			return monitoring.Metric{MetricName: expr, Error: "no namespace enforcement registered for monitoring backend " + mo.primary.Name()}, nil
+		}
+		var err error
+		if expr, err = replaceNamespace(expr, namespace); err != nil {
+			return monitoring.Metric{}, err
+		}
+	}
+	return mo.primary.GetMetric(expr, time), nil
 }
 
 func (mo monitoringOperator) GetMetricOverTime(expr, namespace string, start, end time.Time, step time.Duration) (monitoring.Metric, error) {
-
-	return mo.prometheus.GetMetricOverTime(expr, start, end, step), nil
+	if namespace != "" {
+		replaceNamespace, ok := expressions.ReplaceNamespaceFns[mo.primary.Name()]
+		if !ok {
+			return monitoring.Metric{MetricName: expr, Error: "no namespace enforcement registered for monitoring backend " + mo.primary.Name()}, nil
+		}
+		var err error
+		if expr, err = replaceNamespace(expr, namespace); err != nil {
+			return monitoring.Metric{}, err
+		}
+	}
+	return mo.primary.GetMetricOverTime(expr, start, end, step), nil
 }
 
-func (mo monitoringOperator) GetNamedMetrics(metrics []string, time time.Time, opt monitoring.QueryOption) Metrics {
-	ress := mo.prometheus.GetNamedMetrics(metrics, time, opt)
+func (mo monitoringOperator) GetNamedMetrics(metrics []string, time time.Time, opt monitoring.QueryOption, datasource string) Metrics {
+	if datasource == DataSourceMetricsServer {
+		if mo.metricsServer == nil {
+			return Metrics{Results: metricsServerUnavailable(metrics)}
+		}
+		return Metrics{Results: tagSource(mo.metricsServer.GetNamedMetrics(metrics, time, opt), DataSourceMetricsServer)}
+	}
+
+	ress := tagSource(mo.primary.GetNamedMetrics(metrics, time, opt), mo.primary.Name())
+	if datasource == mo.primary.Name() || mo.metricsServer == nil {
+		return Metrics{Results: ress}
+	}
 
-	return Metrics{Results: ress}
+	missing := missingMetricNames(metrics, ress)
+	if len(missing) == 0 {
+		return Metrics{Results: ress}
+	}
+	fallback := tagSource(mo.metricsServer.GetNamedMetrics(missing, time, opt), DataSourceMetricsServer)
+	return Metrics{Results: mergeMetrics(ress, fallback)}
 }
 
-func (mo monitoringOperator) GetNamedMetricsOverTime(metrics []string, start, end time.Time, step time.Duration, opt monitoring.QueryOption) Metrics {
-	ress := mo.prometheus.GetNamedMetricsOverTime(metrics, start, end, step, opt)
+func (mo monitoringOperator) GetNamedMetricsOverTime(metrics []string, start, end time.Time, step time.Duration, opt monitoring.QueryOption, datasource string) Metrics {
+	if datasource == DataSourceMetricsServer {
+		if mo.metricsServer == nil {
+			return Metrics{Results: metricsServerUnavailable(metrics)}
+		}
+		return Metrics{Results: tagSource(mo.metricsServer.GetNamedMetricsOverTime(metrics, start, end, step, opt), DataSourceMetricsServer)}
+	}
 
-	return Metrics{Results: ress}
+	ress := tagSource(mo.primary.GetNamedMetricsOverTime(metrics, start, end, step, opt), mo.primary.Name())
+	if datasource == mo.primary.Name() || mo.metricsServer == nil {
+		return Metrics{Results: ress}
+	}
+
+	missing := missingMetricNames(metrics, ress)
+	if len(missing) == 0 {
+		return Metrics{Results: ress}
+	}
+	fallback := tagSource(mo.metricsServer.GetNamedMetricsOverTime(missing, start, end, step, opt), DataSourceMetricsServer)
+	return Metrics{Results: mergeMetrics(ress, fallback)}
 }
 
 func (mo monitoringOperator) GetMetadata(namespace string) Metadata {
-	data := mo.prometheus.GetMetadata(namespace)
+	data := mo.primary.GetMetadata(namespace)
 	return Metadata{Data: data}
 }
 
 func (mo monitoringOperator) GetLabelValues(label string, matches []string, start, end time.Time) LabelValues {
-	data := mo.prometheus.GetLabelValues(label, matches, start, end)
+	data := mo.primary.GetLabelValues(label, matches, start, end)
 	return LabelValues{Data: data}
 }
 
@@ -99,16 +182,20 @@ func (mo monitoringOperator) GetMetricLabelSet(metric, namespace string, start,
 	var err error
 	if namespace != "" {
 		// Different monitoring backend implementations have different ways to enforce namespace isolation.
-		// Each implementation should register itself to `ReplaceNamespaceFns` during init().
-		// We hard code "prometheus" here because we only support this datasource so far.
-		// In the future, maybe the value should be returned from a method like `mo.c.GetMonitoringServiceName()`.
-		expr, err = expressions.ReplaceNamespaceFns["prometheus"](metric, namespace)
+		// Each implementation registers itself to `ReplaceNamespaceFns` under its own Name(), so the backend
+		// actually configured decides how the rewrite happens instead of Prometheus always being assumed.
+		replaceNamespace, ok := expressions.ReplaceNamespaceFns[mo.primary.Name()]
+		if !ok {
+			klog.Errorf("no namespace enforcement registered for monitoring backend %q", mo.primary.Name())
+			return MetricLabelSet{}
+		}
+		expr, err = replaceNamespace(metric, namespace)
 		if err != nil {
 			klog.Error(err)
 			return MetricLabelSet{}
 		}
 	}
-	data := mo.prometheus.GetMetricLabelSet(expr, start, end)
+	data := mo.primary.GetMetricLabelSet(expr, start, end)
 	return MetricLabelSet{Data: data}
 }
 
@@ -273,4 +360,4 @@ func (mo monitoringOperator) GetWorkspaceStats(workspace string) Metrics {
 	}
 
 	return res
-}
\ No newline at end of file
+}
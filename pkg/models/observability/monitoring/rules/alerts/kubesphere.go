@@ -0,0 +1,48 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alerts
+
+func init() {
+	Register(
+		Alert{
+			Category: "kubesphere.rules",
+			Name:     "KubeSphereAPIServerDown",
+			Expr:     `absent(up{job="ks-apiserver"} == 1)`,
+			For:      "5m",
+			Severity: "critical",
+			Annotations: map[string]string{
+				"summary":     "ks-apiserver is down",
+				"description": "No ks-apiserver instance has been up for the last 5 minutes.",
+			},
+			RunbookURL: "https://kubesphere.io/docs/v3.4/reference/alerting-rules/#kubesphereapiserverdown",
+		},
+		Alert{
+			Category: "kubesphere.rules",
+			Name:     "KubeSphereMonitoringAPIHighErrorRate",
+			Expr: `sum(rate(ks_apiserver_request_total{component="monitoring",code=~"5.."}[5m]))
+  /
+sum(rate(ks_apiserver_request_total{component="monitoring"}[5m])) > 0.05`,
+			For:      "10m",
+			Severity: "warning",
+			Annotations: map[string]string{
+				"summary":     "High 5xx rate on the monitoring API",
+				"description": "More than 5% of requests to the monitoring API have failed with a 5xx status over the last 10 minutes.",
+			},
+			RunbookURL: "https://kubesphere.io/docs/v3.4/reference/alerting-rules/#kubespheremonitoringapihigherrorrate",
+		},
+	)
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package alerts holds the registry of Prometheus alerting rules
+// KubeSphere ships. Call Register from an init() to add to it;
+// rules.BuildPrometheusRule reads the registry back out.
+package alerts
+
+// Alert is one entry registered via Register.
+type Alert struct {
+	Category    string
+	Name        string
+	Expr        string
+	For         string
+	Severity    string
+	Labels      map[string]string
+	Annotations map[string]string
+	RunbookURL  string
+}
+
+var registry []Alert
+
+// Register appends a to the alerts registry. It is meant to be called
+// from package init() functions, not at runtime.
+func Register(a ...Alert) {
+	registry = append(registry, a...)
+}
+
+// List returns every alert registered so far.
+func List() []Alert {
+	return registry
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"fmt"
+	"sort"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"kubesphere.io/kubesphere/pkg/models/observability/monitoring/rules/alerts"
+	"kubesphere.io/kubesphere/pkg/models/observability/monitoring/rules/recordingrules"
+)
+
+// BuildPrometheusRule assembles every registered recording rule and
+// alert into a single PrometheusRule named name in namespace, one
+// monitoringv1.RuleGroup per Category so operators can reason about
+// (and silence) them as a unit.
+func BuildPrometheusRule(namespace, name string) (*monitoringv1.PrometheusRule, error) {
+	groups := map[string]*monitoringv1.RuleGroup{}
+	var order []string
+
+	group := func(category string) *monitoringv1.RuleGroup {
+		g, ok := groups[category]
+		if !ok {
+			g = &monitoringv1.RuleGroup{Name: category}
+			groups[category] = g
+			order = append(order, category)
+		}
+		return g
+	}
+
+	for _, rr := range recordingrules.List() {
+		if rr.Name == "" || rr.Expr == "" {
+			return nil, fmt.Errorf("recording rule %q is missing a name or expression", rr.Name)
+		}
+		g := group(rr.Category)
+		g.Rules = append(g.Rules, monitoringv1.Rule{
+			Record: rr.Name,
+			Expr:   intstr.FromString(rr.Expr),
+			Labels: rr.Labels,
+		})
+	}
+
+	for _, a := range alerts.List() {
+		if a.Name == "" || a.Expr == "" {
+			return nil, fmt.Errorf("alert %q is missing a name or expression", a.Name)
+		}
+		labels := map[string]string{"severity": a.Severity}
+		for k, v := range a.Labels {
+			labels[k] = v
+		}
+		annotations := map[string]string{}
+		for k, v := range a.Annotations {
+			annotations[k] = v
+		}
+		if a.RunbookURL != "" {
+			annotations["runbook_url"] = a.RunbookURL
+		}
+
+		g := group(a.Category)
+		g.Rules = append(g.Rules, monitoringv1.Rule{
+			Alert:       a.Name,
+			Expr:        intstr.FromString(a.Expr),
+			For:         monitoringv1.Duration(a.For),
+			Labels:      labels,
+			Annotations: annotations,
+		})
+	}
+
+	sort.Strings(order)
+	spec := monitoringv1.PrometheusRuleSpec{}
+	for _, category := range order {
+		spec.Groups = append(spec.Groups, *groups[category])
+	}
+
+	return &monitoringv1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+		Spec: spec,
+	}, nil
+}
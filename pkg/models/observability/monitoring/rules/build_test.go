@@ -0,0 +1,124 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"strings"
+	"testing"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+
+	"kubesphere.io/kubesphere/pkg/models/observability/monitoring/rules/alerts"
+	"kubesphere.io/kubesphere/pkg/models/observability/monitoring/rules/recordingrules"
+)
+
+func TestBuildPrometheusRuleKnownAlertsAndRecordingRules(t *testing.T) {
+	pr, err := BuildPrometheusRule("kubesphere-monitoring-system", "kubesphere-rules")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if pr.Namespace != "kubesphere-monitoring-system" || pr.Name != "kubesphere-rules" {
+		t.Fatalf("unexpected object meta: %+v", pr.ObjectMeta)
+	}
+
+	if len(pr.Spec.Groups) != 1 || pr.Spec.Groups[0].Name != "kubesphere.rules" {
+		t.Fatalf("expected a single kubesphere.rules group, got %+v", pr.Spec.Groups)
+	}
+
+	var recordRule, alertRule *monitoringv1.Rule
+	for i, r := range pr.Spec.Groups[0].Rules {
+		switch {
+		case r.Record == "kubesphere:cluster:count":
+			recordRule = &pr.Spec.Groups[0].Rules[i]
+		case r.Alert == "KubeSphereAPIServerDown":
+			alertRule = &pr.Spec.Groups[0].Rules[i]
+		}
+	}
+
+	if recordRule == nil {
+		t.Fatal("expected kubesphere:cluster:count recording rule to be present")
+	}
+	if recordRule.Expr.String() != "sum(kubesphere_cluster_count)" {
+		t.Fatalf("unexpected recording rule expr: %s", recordRule.Expr.String())
+	}
+
+	if alertRule == nil {
+		t.Fatal("expected KubeSphereAPIServerDown alert to be present")
+	}
+	if alertRule.For != monitoringv1.Duration("5m") {
+		t.Fatalf("expected For to be converted to monitoringv1.Duration(\"5m\"), got %v", alertRule.For)
+	}
+	if alertRule.Labels["severity"] != "critical" {
+		t.Fatalf("expected severity label to be set from Alert.Severity, got %v", alertRule.Labels)
+	}
+	if alertRule.Annotations["runbook_url"] == "" {
+		t.Fatalf("expected RunbookURL to be merged into annotations, got %v", alertRule.Annotations)
+	}
+	if alertRule.Annotations["summary"] != "ks-apiserver is down" {
+		t.Fatalf("expected Annotations to be carried through, got %v", alertRule.Annotations)
+	}
+}
+
+func TestBuildPrometheusRuleGroupsByCategorySortedOrder(t *testing.T) {
+	recordingrules.Register(recordingrules.RecordingRule{
+		Category: "zzz-extra.rules",
+		Name:     "zzz:extra:count",
+		Expr:     "sum(zzz_extra_count)",
+	})
+
+	pr, err := BuildPrometheusRule("kubesphere-monitoring-system", "kubesphere-rules")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(pr.Spec.Groups) != 2 {
+		t.Fatalf("expected 2 groups after adding a new category, got %d", len(pr.Spec.Groups))
+	}
+	if pr.Spec.Groups[0].Name != "kubesphere.rules" || pr.Spec.Groups[1].Name != "zzz-extra.rules" {
+		t.Fatalf("expected groups sorted alphabetically by category, got %v, %v", pr.Spec.Groups[0].Name, pr.Spec.Groups[1].Name)
+	}
+}
+
+func TestBuildPrometheusRuleValidatesEmptyExpr(t *testing.T) {
+	alerts.Register(alerts.Alert{
+		Category: "kubesphere.rules",
+		Name:     "MissingExprAlert",
+	})
+
+	_, err := BuildPrometheusRule("kubesphere-monitoring-system", "kubesphere-rules")
+	if err == nil {
+		t.Fatal("expected an error for an alert with a missing expression")
+	}
+	if !strings.Contains(err.Error(), "MissingExprAlert") {
+		t.Fatalf("expected error to name the offending alert, got %q", err.Error())
+	}
+}
+
+// This runs last: once a recording rule with a missing name is
+// registered, every later BuildPrometheusRule call in this binary
+// fails fast on it before reaching the alerts loop.
+func TestBuildPrometheusRuleValidatesEmptyName(t *testing.T) {
+	recordingrules.Register(recordingrules.RecordingRule{
+		Category: "kubesphere.rules",
+		Expr:     "sum(unnamed_metric)",
+	})
+
+	if _, err := BuildPrometheusRule("kubesphere-monitoring-system", "kubesphere-rules"); err == nil {
+		t.Fatal("expected an error for a recording rule with a missing name")
+	}
+}
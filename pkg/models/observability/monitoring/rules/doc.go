@@ -0,0 +1,21 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rules assembles the recording rules and alerts registered by
+// its recordingrules and alerts subpackages into a single PrometheusRule
+// custom resource, following the same registry-then-build pattern the
+// KubeVirt SSP operator uses for its own operator-observability rules.
+package rules
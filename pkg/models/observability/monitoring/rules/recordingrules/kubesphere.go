@@ -0,0 +1,61 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recordingrules
+
+// These rules turn the object counts the ksstate collector exposes on
+// /ks-metrics into stable, cheap-to-query series, the same counts that
+// GetKubeSphereStats/GetWorkspaceStats compute on every call. Console dashboards should read these recording
+// rules instead of the legacy JSON stats endpoints where possible.
+func init() {
+	Register(
+		RecordingRule{
+			Category: "kubesphere.rules",
+			Name:     "kubesphere:cluster:count",
+			Expr:     "sum(kubesphere_cluster_count)",
+		},
+		RecordingRule{
+			Category: "kubesphere.rules",
+			Name:     "kubesphere:workspace:count",
+			Expr:     "sum(kubesphere_workspace_count)",
+		},
+		RecordingRule{
+			Category: "kubesphere.rules",
+			Name:     "kubesphere:user:count",
+			Expr:     "sum(kubesphere_user_count)",
+		},
+		RecordingRule{
+			Category: "kubesphere.rules",
+			Name:     "kubesphere:workspace:namespace:count",
+			Expr:     "sum(kubesphere_workspace_namespace_count) by (workspace)",
+		},
+		RecordingRule{
+			Category: "kubesphere.rules",
+			Name:     "kubesphere:workspace:devops_project:count",
+			Expr:     "sum(kubesphere_workspace_devops_project_count) by (workspace)",
+		},
+		RecordingRule{
+			Category: "kubesphere.rules",
+			Name:     "kubesphere:workspace:role:count",
+			Expr:     "sum(kubesphere_workspace_role_count) by (workspace)",
+		},
+		RecordingRule{
+			Category: "kubesphere.rules",
+			Name:     "kubesphere:workspace:member:count",
+			Expr:     "sum(kubesphere_workspace_member_count) by (workspace)",
+		},
+	)
+}
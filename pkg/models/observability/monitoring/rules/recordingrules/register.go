@@ -0,0 +1,43 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package recordingrules holds the registry of Prometheus recording
+// rules KubeSphere ships. Call Register from an init() to add to it;
+// rules.BuildPrometheusRule reads the registry back out.
+package recordingrules
+
+// RecordingRule is one entry registered via Register.
+type RecordingRule struct {
+	// Category groups related rules into the same PrometheusRule rule
+	// group, e.g. "kubesphere.rules".
+	Category string
+	Name     string
+	Expr     string
+	Labels   map[string]string
+}
+
+var registry []RecordingRule
+
+// Register appends rr to the recording-rules registry. It is meant to
+// be called from package init() functions, not at runtime.
+func Register(rr ...RecordingRule) {
+	registry = append(registry, rr...)
+}
+
+// List returns every recording rule registered so far.
+func List() []RecordingRule {
+	return registry
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2019 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"kubesphere.io/kubesphere/pkg/simple/client/observability/monitoring"
+)
+
+// Metrics wraps the named-metric results returned to API callers.
+type Metrics struct {
+	Results []monitoring.Metric `json:"results" description:"Query results"`
+}
+
+// Metadata wraps the metric metadata returned to API callers.
+type Metadata struct {
+	Data []monitoring.Metadata `json:"data,omitempty" description:"Metadata results"`
+}
+
+// LabelValues wraps the label value results returned to API callers.
+type LabelValues struct {
+	Data []string `json:"data,omitempty" description:"Label values"`
+}
+
+// MetricLabelSet wraps the label set results returned to API callers.
+type MetricLabelSet struct {
+	Data []map[string]string `json:"data,omitempty" description:"Label sets"`
+}
+
+// KubeSphereStats/WorkspaceStats metric names, as surfaced by the legacy
+// JSON API and, identically, by the ksstate Prometheus collector.
+const (
+	KubeSphereClusterCount   = "kubesphere_cluster_count"
+	KubeSphereWorkspaceCount = "kubesphere_workspace_count"
+	KubeSphereUserCount      = "kubesphere_user_count"
+
+	WorkspaceNamespaceCount = "kubesphere_workspace_namespace_count"
+	WorkspaceDevopsCount    = "kubesphere_workspace_devops_project_count"
+	WorkspaceMemberCount    = "kubesphere_workspace_member_count"
+	WorkspaceRoleCount      = "kubesphere_workspace_role_count"
+)
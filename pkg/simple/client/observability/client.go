@@ -0,0 +1,51 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package observability is the composition root that turns monitoring
+// options into a concrete monitoring.Interface. It is the one place
+// allowed to import every backend package, so that none of the backend
+// packages, or the monitoring contract package itself, need to know the
+// others exist.
+package observability
+
+import (
+	"fmt"
+
+	"kubesphere.io/kubesphere/pkg/simple/client/observability/monitoring"
+	"kubesphere.io/kubesphere/pkg/simple/client/observability/monitoring/mimir"
+	"kubesphere.io/kubesphere/pkg/simple/client/observability/monitoring/thanos"
+	"kubesphere.io/kubesphere/pkg/simple/client/observability/monitoring/victoriametrics"
+)
+
+// NewMonitoringClient builds the monitoring.Interface selected by
+// opts.Backend for the thanos, victoria-metrics and mimir backends. For
+// prometheus, or an empty Backend, it builds nothing and returns an
+// error.
+func NewMonitoringClient(opts *monitoring.Options) (monitoring.Interface, error) {
+	switch opts.Backend {
+	case monitoring.BackendThanos:
+		return thanos.New(thanos.Options{Address: opts.Endpoint, Tenant: opts.Tenant})
+	case monitoring.BackendVictoriaMetrics:
+		return victoriametrics.New(opts.Endpoint)
+	case monitoring.BackendMimir:
+		return mimir.New(mimir.Options{Address: opts.Endpoint, OrgID: opts.OrgID})
+	case monitoring.BackendPrometheus, "":
+		return nil, fmt.Errorf("the prometheus backend client is constructed by its own caller; " +
+			"NewMonitoringClient only builds the newer pluggable backends")
+	default:
+		return nil, fmt.Errorf("unsupported monitoring backend %q", opts.Backend)
+	}
+}
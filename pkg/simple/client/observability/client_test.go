@@ -0,0 +1,104 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package observability
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"kubesphere.io/kubesphere/pkg/simple/client/observability/monitoring"
+)
+
+// TestBackendsSendBackendAppropriateWireCalls asserts that the same
+// logical query - "give me this metric" - results in a different wire
+// call depending on which backend the client was built for.
+func TestBackendsSendBackendAppropriateWireCalls(t *testing.T) {
+	cases := []struct {
+		backend string
+		extra   map[string]string // extra Options fields, keyed by field name
+		check   func(t *testing.T, r *http.Request)
+	}{
+		{
+			backend: monitoring.BackendThanos,
+			check: func(t *testing.T, r *http.Request) {
+				if r.URL.Query().Get("query.partial-response") != "false" {
+					t.Errorf("thanos request missing query.partial-response=false, got %s", r.URL.RawQuery)
+				}
+			},
+		},
+		{
+			backend: monitoring.BackendMimir,
+			extra:   map[string]string{"OrgID": "tenant-a"},
+			check: func(t *testing.T, r *http.Request) {
+				if got := r.Header.Get("X-Scope-OrgID"); got != "tenant-a" {
+					t.Errorf("mimir request missing X-Scope-OrgID header, got %q", got)
+				}
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.backend, func(t *testing.T) {
+			var captured *http.Request
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				captured = r
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+			}))
+			defer srv.Close()
+
+			opts := &monitoring.Options{Backend: tc.backend, Endpoint: srv.URL, OrgID: tc.extra["OrgID"]}
+			client, err := NewMonitoringClient(opts)
+			if err != nil {
+				t.Fatalf("NewMonitoringClient: %v", err)
+			}
+
+			client.GetMetric("up", time.Now())
+
+			if captured == nil {
+				t.Fatal("backend never made a request")
+			}
+			tc.check(t, captured)
+		})
+	}
+}
+
+func TestVictoriaMetricsAddsExtraLabelForNamedMetrics(t *testing.T) {
+	var captured *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		captured = r
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewMonitoringClient(&monitoring.Options{Backend: monitoring.BackendVictoriaMetrics, Endpoint: srv.URL})
+	if err != nil {
+		t.Fatalf("NewMonitoringClient: %v", err)
+	}
+
+	client.GetNamedMetrics([]string{"up"}, time.Now(), monitoring.QueryOption{NamespaceName: "demo-ns"})
+
+	if captured == nil {
+		t.Fatal("backend never made a request")
+	}
+	if got := captured.URL.Query().Get("extra_label"); got != "namespace=demo-ns" {
+		t.Errorf("victoriametrics request missing extra_label=namespace=demo-ns, got %q", got)
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2019 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package monitoring declares the contract every monitoring backend
+// (Prometheus, the Kubernetes Metrics Server, Thanos, ...) must satisfy
+// so that pkg/models/observability/monitoring can query them
+// interchangeably.
+package monitoring
+
+import "time"
+
+// Backend identifiers, as returned by Interface.Name(). They double as
+// the value of the monitoring options' --monitoring-backend flag and as
+// the key into expressions.ReplaceNamespaceFns.
+const (
+	BackendPrometheus      = "prometheus"
+	BackendThanos          = "thanos"
+	BackendVictoriaMetrics = "victoria-metrics"
+	BackendMimir           = "mimir"
+)
+
+// Interface is implemented once per supported monitoring backend.
+type Interface interface {
+	// Name identifies which backend this implementation talks to, e.g.
+	// one of the Backend* constants above. monitoringOperator uses it to
+	// look up the right namespace-enforcement strategy for the backend
+	// it was configured with, instead of assuming Prometheus.
+	Name() string
+
+	GetMetric(expr string, time time.Time) Metric
+	GetMetricOverTime(expr string, start, end time.Time, step time.Duration) Metric
+	GetNamedMetrics(metrics []string, time time.Time, opt QueryOption) []Metric
+	GetNamedMetricsOverTime(metrics []string, start, end time.Time, step time.Duration, opt QueryOption) []Metric
+	GetMetadata(namespace string) []Metadata
+	GetLabelValues(label string, matches []string, start, end time.Time) []string
+	GetMetricLabelSet(expr string, start, end time.Time) []map[string]string
+}
+
+// QueryOption narrows a named-metric query down to a specific resource.
+// Backends that cannot honor a given field (e.g. the Metrics Server has
+// no notion of workspaces) simply ignore it.
+type QueryOption struct {
+	NamespaceName string
+	NodeName      string
+	WorkloadName  string
+	PodName       string
+	ContainerName string
+}
+
+// MetricType mirrors the value_type field of a Prometheus query result.
+type MetricType string
+
+const (
+	MetricTypeVector MetricType = "vector"
+	MetricTypeMatrix MetricType = "matrix"
+)
+
+// Point is a single (timestamp, value) sample, matching Prometheus' own
+// [seconds, "value"] wire tuple.
+type Point [2]float64
+
+// MetricValue holds either an instant sample or a range of samples,
+// optionally tagged with the series' label set.
+type MetricValue struct {
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Sample   *Point            `json:"value,omitempty"`
+	Series   []Point           `json:"values,omitempty"`
+}
+
+// MetricData is the value_type/values payload of a single metric result.
+type MetricData struct {
+	MetricType   MetricType    `json:"resultType"`
+	MetricValues []MetricValue `json:"result"`
+}
+
+// Metric is one named query's result, or its error if the query failed.
+type Metric struct {
+	MetricName string `json:"metric_name,omitempty"`
+	MetricData `json:"data,omitempty"`
+	Error      string `json:"error,omitempty"`
+	// Source names the backend that actually answered this metric, e.g.
+	// "prometheus" or "metrics-server". Populated by callers that query
+	// more than one backend; left empty otherwise.
+	Source string `json:"source,omitempty"`
+}
+
+// Metadata describes a single metric as reported by a backend's metadata
+// endpoint (e.g. Prometheus' /api/v1/metadata).
+type Metadata struct {
+	Metric string `json:"metric,omitempty"`
+	Type   string `json:"type,omitempty"`
+	Help   string `json:"help,omitempty"`
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metricsserver implements monitoring.Interface on top of the
+// metrics.k8s.io/v1beta1 API. It only ever has "now" data - no range
+// queries, no metadata, no label search - so it is meant to be used as
+// a best-effort fallback for the handful of node/pod resource metrics
+// it can answer, not as a full Prometheus replacement.
+package metricsserver
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsclient "k8s.io/metrics/pkg/client/clientset/versioned"
+
+	"kubesphere.io/kubesphere/pkg/simple/client/observability/monitoring"
+)
+
+// Supported is the set of named metrics this backend can answer. Any
+// other name yields an empty result, so callers should only fall back
+// to this client for names in this set.
+var Supported = map[string]bool{
+	NodeCPUUsage:    true,
+	NodeMemoryUsage: true,
+	PodCPUUsage:     true,
+	PodMemoryUsage:  true,
+}
+
+const (
+	NodeCPUUsage    = "node_cpu_usage"
+	NodeMemoryUsage = "node_memory_usage"
+	PodCPUUsage     = "pod_cpu_usage"
+	PodMemoryUsage  = "pod_memory_usage"
+)
+
+type metricsServer struct {
+	client metricsclient.Interface
+}
+
+// New builds a monitoring.Interface backed by the given metrics.k8s.io
+// client.
+func New(client metricsclient.Interface) monitoring.Interface {
+	return &metricsServer{client: client}
+}
+
+func (m *metricsServer) Name() string {
+	return "metrics-server"
+}
+
+func (m *metricsServer) GetNamedMetrics(metrics []string, t time.Time, opt monitoring.QueryOption) []monitoring.Metric {
+	var results []monitoring.Metric
+	for _, name := range metrics {
+		if !Supported[name] {
+			continue
+		}
+		results = append(results, m.getNamedMetric(name, t, opt))
+	}
+	return results
+}
+
+func (m *metricsServer) getNamedMetric(name string, t time.Time, opt monitoring.QueryOption) monitoring.Metric {
+	switch name {
+	case NodeCPUUsage, NodeMemoryUsage:
+		return m.getNodeMetric(name, t, opt)
+	case PodCPUUsage, PodMemoryUsage:
+		return m.getPodMetric(name, t, opt)
+	default:
+		return monitoring.Metric{MetricName: name}
+	}
+}
+
+func (m *metricsServer) getNodeMetric(name string, t time.Time, opt monitoring.QueryOption) monitoring.Metric {
+	if opt.NodeName != "" {
+		nm, err := m.client.MetricsV1beta1().NodeMetricses().Get(context.Background(), opt.NodeName, metav1.GetOptions{})
+		if err != nil {
+			return monitoring.Metric{MetricName: name, Error: err.Error()}
+		}
+		return monitoring.Metric{MetricName: name, MetricData: nodeMetricData(name, []metricsv1beta1.NodeMetrics{*nm})}
+	}
+
+	list, err := m.client.MetricsV1beta1().NodeMetricses().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return monitoring.Metric{MetricName: name, Error: err.Error()}
+	}
+	return monitoring.Metric{MetricName: name, MetricData: nodeMetricData(name, list.Items)}
+}
+
+func (m *metricsServer) getPodMetric(name string, t time.Time, opt monitoring.QueryOption) monitoring.Metric {
+	if opt.PodName != "" {
+		pm, err := m.client.MetricsV1beta1().PodMetricses(opt.NamespaceName).Get(context.Background(), opt.PodName, metav1.GetOptions{})
+		if err != nil {
+			return monitoring.Metric{MetricName: name, Error: err.Error()}
+		}
+		return monitoring.Metric{MetricName: name, MetricData: podMetricData(name, []metricsv1beta1.PodMetrics{*pm})}
+	}
+
+	list, err := m.client.MetricsV1beta1().PodMetricses(opt.NamespaceName).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return monitoring.Metric{MetricName: name, Error: err.Error()}
+	}
+	return monitoring.Metric{MetricName: name, MetricData: podMetricData(name, list.Items)}
+}
+
+func nodeMetricData(name string, items []metricsv1beta1.NodeMetrics) monitoring.MetricData {
+	values := make([]monitoring.MetricValue, 0, len(items))
+	for _, node := range items {
+		var v float64
+		switch name {
+		case NodeCPUUsage:
+			v = node.Usage.Cpu().AsApproximateFloat64()
+		case NodeMemoryUsage:
+			v = node.Usage.Memory().AsApproximateFloat64()
+		}
+		values = append(values, monitoring.MetricValue{
+			Metadata: map[string]string{"node": node.Name},
+			Sample:   &monitoring.Point{float64(node.Timestamp.Unix()), v},
+		})
+	}
+	return monitoring.MetricData{MetricType: monitoring.MetricTypeVector, MetricValues: values}
+}
+
+func podMetricData(name string, items []metricsv1beta1.PodMetrics) monitoring.MetricData {
+	values := make([]monitoring.MetricValue, 0, len(items))
+	for _, pod := range items {
+		var v float64
+		for _, c := range pod.Containers {
+			switch name {
+			case PodCPUUsage:
+				v += c.Usage.Cpu().AsApproximateFloat64()
+			case PodMemoryUsage:
+				v += c.Usage.Memory().AsApproximateFloat64()
+			}
+		}
+		values = append(values, monitoring.MetricValue{
+			Metadata: map[string]string{"namespace": pod.Namespace, "pod": pod.Name},
+			Sample:   &monitoring.Point{float64(pod.Timestamp.Unix()), v},
+		})
+	}
+	return monitoring.MetricData{MetricType: monitoring.MetricTypeVector, MetricValues: values}
+}
+
+// The remaining Interface methods have no equivalent in metrics.k8s.io;
+// they return zero values so a metricsServer can stand in for
+// monitoring.Interface without callers needing to type-switch.
+
+func (m *metricsServer) GetMetric(expr string, time time.Time) monitoring.Metric {
+	return monitoring.Metric{}
+}
+
+func (m *metricsServer) GetMetricOverTime(expr string, start, end time.Time, step time.Duration) monitoring.Metric {
+	return monitoring.Metric{}
+}
+
+func (m *metricsServer) GetNamedMetricsOverTime(metrics []string, start, end time.Time, step time.Duration, opt monitoring.QueryOption) []monitoring.Metric {
+	return nil
+}
+
+func (m *metricsServer) GetMetadata(namespace string) []monitoring.Metadata {
+	return nil
+}
+
+func (m *metricsServer) GetLabelValues(label string, matches []string, start, end time.Time) []string {
+	return nil
+}
+
+func (m *metricsServer) GetMetricLabelSet(expr string, start, end time.Time) []map[string]string {
+	return nil
+}
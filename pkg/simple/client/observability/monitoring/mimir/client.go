@@ -0,0 +1,65 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package mimir implements monitoring.Interface against a Grafana Mimir
+// (or Cortex) query frontend. Mimir multi-tenancy is orthogonal to
+// KubeSphere namespace enforcement: OrgID identifies the Mimir tenant a
+// whole KubeSphere deployment was provisioned against, so it is set
+// once per Client, while per-namespace isolation still goes through
+// expressions.ReplaceNamespaceFns like every other backend.
+package mimir
+
+import (
+	"net/http"
+
+	"kubesphere.io/kubesphere/pkg/simple/client/observability/monitoring"
+	"kubesphere.io/kubesphere/pkg/simple/client/observability/monitoring/promapi"
+)
+
+// Options configures a Mimir Client.
+type Options struct {
+	// Address of the Mimir query frontend, e.g. http://mimir-query-frontend:8080/prometheus.
+	Address string
+	// OrgID is sent as the X-Scope-OrgID header on every request.
+	OrgID string
+}
+
+type Client struct {
+	promapi.Client
+}
+
+func New(opts Options) (monitoring.Interface, error) {
+	c, err := promapi.NewClient(opts.Address, orgIDRoundTripper{orgID: opts.OrgID})
+	if err != nil {
+		return nil, err
+	}
+	return &Client{Client: c}, nil
+}
+
+func (c *Client) Name() string {
+	return monitoring.BackendMimir
+}
+
+// orgIDRoundTripper sets Mimir/Cortex's tenant header on every outgoing
+// request.
+type orgIDRoundTripper struct {
+	orgID string
+}
+
+func (rt orgIDRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.Header.Set("X-Scope-OrgID", rt.orgID)
+	return http.DefaultTransport.RoundTrip(req)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+// Options selects and configures the monitoring backend ks-apiserver
+// queries.
+type Options struct {
+	// Backend is one of the Backend* constants. Defaults to
+	// BackendPrometheus.
+	Backend string
+	// Endpoint is the monitoring backend's query API address.
+	Endpoint string
+	// Tenant is forwarded to the Thanos backend as an external-labels
+	// tenant matcher. Ignored by every other backend.
+	Tenant string
+	// OrgID is forwarded to the Mimir/Cortex backend as X-Scope-OrgID.
+	// Ignored by every other backend.
+	OrgID string
+}
+
+func NewOptions() *Options {
+	return &Options{Backend: BackendPrometheus}
+}
+
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Backend, "monitoring-backend", o.Backend,
+		fmt.Sprintf("Monitoring backend to query. One of %s, %s, %s, %s.",
+			BackendPrometheus, BackendThanos, BackendVictoriaMetrics, BackendMimir))
+	fs.StringVar(&o.Endpoint, "monitoring-endpoint", o.Endpoint, "Address of the monitoring backend's query API.")
+	fs.StringVar(&o.Tenant, "monitoring-tenant", o.Tenant, "Tenant matcher to add to every query. Only used by the thanos backend.")
+	fs.StringVar(&o.OrgID, "monitoring-org-id", o.OrgID, "X-Scope-OrgID to send with every query. Only used by the mimir backend.")
+}
+
+func (o *Options) Validate() []error {
+	switch o.Backend {
+	case BackendPrometheus, BackendThanos, BackendVictoriaMetrics, BackendMimir:
+	default:
+		return []error{fmt.Errorf("unsupported monitoring backend %q", o.Backend)}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promapi
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	papi "github.com/prometheus/client_golang/api"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+
+	"kubesphere.io/kubesphere/pkg/simple/client/observability/monitoring"
+)
+
+// Client implements every monitoring.Interface method except Name()
+// against any backend that speaks the Prometheus HTTP API - which
+// Prometheus, Thanos, VictoriaMetrics and Mimir/Cortex all do. Backend
+// packages embed Client and add their own Name() plus whatever
+// request-shaping (headers, extra query params) their backend prefers
+// on top of plain PromQL.
+type Client struct {
+	API promv1.API
+}
+
+// NewClient dials address, sending every request through roundTripper
+// (nil means the default transport).
+func NewClient(address string, roundTripper http.RoundTripper) (Client, error) {
+	c, err := papi.NewClient(papi.Config{Address: address, RoundTripper: roundTripper})
+	if err != nil {
+		return Client{}, err
+	}
+	return Client{API: promv1.NewAPI(c)}, nil
+}
+
+func (c Client) GetMetric(expr string, t time.Time) monitoring.Metric {
+	value, _, err := c.API.Query(context.Background(), expr, t)
+	return ToMetric(expr, value, err)
+}
+
+func (c Client) GetMetricOverTime(expr string, start, end time.Time, step time.Duration) monitoring.Metric {
+	value, _, err := c.API.QueryRange(context.Background(), expr, promv1.Range{Start: start, End: end, Step: step})
+	return ToMetric(expr, value, err)
+}
+
+func (c Client) GetNamedMetrics(metrics []string, t time.Time, opt monitoring.QueryOption) []monitoring.Metric {
+	results := make([]monitoring.Metric, 0, len(metrics))
+	for _, name := range metrics {
+		results = append(results, c.GetMetric(name, t))
+	}
+	return results
+}
+
+func (c Client) GetNamedMetricsOverTime(metrics []string, start, end time.Time, step time.Duration, opt monitoring.QueryOption) []monitoring.Metric {
+	results := make([]monitoring.Metric, 0, len(metrics))
+	for _, name := range metrics {
+		results = append(results, c.GetMetricOverTime(name, start, end, step))
+	}
+	return results
+}
+
+func (c Client) GetMetadata(namespace string) []monitoring.Metadata {
+	md, err := c.API.TargetsMetadata(context.Background(), "", "", "")
+	if err != nil {
+		return nil
+	}
+	out := make([]monitoring.Metadata, 0, len(md))
+	for _, m := range md {
+		out = append(out, monitoring.Metadata{Metric: m.Metric, Type: string(m.Type), Help: m.Help})
+	}
+	return out
+}
+
+func (c Client) GetLabelValues(label string, matches []string, start, end time.Time) []string {
+	values, _, err := c.API.LabelValues(context.Background(), label, matches, start, end)
+	if err != nil {
+		return nil
+	}
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		out = append(out, string(v))
+	}
+	return out
+}
+
+func (c Client) GetMetricLabelSet(expr string, start, end time.Time) []map[string]string {
+	series, _, err := c.API.Series(context.Background(), []string{expr}, start, end)
+	if err != nil {
+		return nil
+	}
+	out := make([]map[string]string, 0, len(series))
+	for _, s := range series {
+		set := make(map[string]string, len(s))
+		for k, v := range s {
+			set[string(k)] = string(v)
+		}
+		out = append(out, set)
+	}
+	return out
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package promapi converts github.com/prometheus/client_golang's query
+// results into monitoring.Metric. It exists so that every backend
+// speaking the Prometheus HTTP API (Prometheus itself, Thanos,
+// VictoriaMetrics, Mimir/Cortex) can share one converter instead of
+// each reimplementing it.
+package promapi
+
+import (
+	"github.com/prometheus/common/model"
+
+	"kubesphere.io/kubesphere/pkg/simple/client/observability/monitoring"
+)
+
+// ToMetric converts a query's raw model.Value into monitoring.Metric.
+// A query error is recorded on the returned Metric rather than
+// propagated, matching how the rest of monitoring.Interface reports
+// per-metric failures.
+func ToMetric(name string, value model.Value, err error) monitoring.Metric {
+	if err != nil {
+		return monitoring.Metric{MetricName: name, Error: err.Error()}
+	}
+
+	switch v := value.(type) {
+	case model.Vector:
+		return monitoring.Metric{MetricName: name, MetricData: vectorToData(v)}
+	case model.Matrix:
+		return monitoring.Metric{MetricName: name, MetricData: matrixToData(v)}
+	default:
+		return monitoring.Metric{MetricName: name, Error: "unsupported result type"}
+	}
+}
+
+func vectorToData(v model.Vector) monitoring.MetricData {
+	values := make([]monitoring.MetricValue, 0, len(v))
+	for _, sample := range v {
+		values = append(values, monitoring.MetricValue{
+			Metadata: labelsToMap(sample.Metric),
+			Sample:   &monitoring.Point{float64(sample.Timestamp.Unix()), float64(sample.Value)},
+		})
+	}
+	return monitoring.MetricData{MetricType: monitoring.MetricTypeVector, MetricValues: values}
+}
+
+func matrixToData(m model.Matrix) monitoring.MetricData {
+	values := make([]monitoring.MetricValue, 0, len(m))
+	for _, series := range m {
+		points := make([]monitoring.Point, 0, len(series.Values))
+		for _, p := range series.Values {
+			points = append(points, monitoring.Point{float64(p.Timestamp.Unix()), float64(p.Value)})
+		}
+		values = append(values, monitoring.MetricValue{
+			Metadata: labelsToMap(series.Metric),
+			Series:   points,
+		})
+	}
+	return monitoring.MetricData{MetricType: monitoring.MetricTypeMatrix, MetricValues: values}
+}
+
+func labelsToMap(ls model.Metric) map[string]string {
+	out := make(map[string]string, len(ls))
+	for k, v := range ls {
+		out[string(k)] = string(v)
+	}
+	return out
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package thanos implements monitoring.Interface against a Thanos
+// Querier, which speaks the same HTTP API as Prometheus. On top of that
+// shared API it always disables partial responses
+// (query.partial-response=false), so a store that is temporarily
+// unreachable fails the query loudly instead of quietly shrinking the
+// result set, and, when Tenant is set, adds a tenant="<value>" matcher
+// so a single Thanos deployment can be carved up per tenant the same
+// way namespace enforcement carves it up per namespace.
+package thanos
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"kubesphere.io/kubesphere/pkg/simple/client/observability/monitoring"
+	"kubesphere.io/kubesphere/pkg/simple/client/observability/monitoring/promapi"
+)
+
+// Options configures a Thanos Client.
+type Options struct {
+	// Address of the Thanos Querier, e.g. http://thanos-query:9090.
+	Address string
+	// Tenant, if set, is injected as a tenant="<value>" matcher, on top
+	// of namespace enforcement, into the expressions of GetMetric,
+	// GetMetricOverTime and GetMetricLabelSet. Named-metric, metadata and
+	// label-value queries are sent without it.
+	Tenant string
+}
+
+type Client struct {
+	promapi.Client
+	tenant string
+}
+
+func New(opts Options) (monitoring.Interface, error) {
+	c, err := promapi.NewClient(opts.Address, partialResponseRoundTripper{})
+	if err != nil {
+		return nil, err
+	}
+	return &Client{Client: c, tenant: opts.Tenant}, nil
+}
+
+func (c *Client) Name() string {
+	return monitoring.BackendThanos
+}
+
+func (c *Client) GetMetric(expr string, t time.Time) monitoring.Metric {
+	return c.Client.GetMetric(c.withTenant(expr), t)
+}
+
+func (c *Client) GetMetricOverTime(expr string, start, end time.Time, step time.Duration) monitoring.Metric {
+	return c.Client.GetMetricOverTime(c.withTenant(expr), start, end, step)
+}
+
+func (c *Client) GetMetricLabelSet(expr string, start, end time.Time) []map[string]string {
+	return c.Client.GetMetricLabelSet(c.withTenant(expr), start, end)
+}
+
+func (c *Client) withTenant(expr string) string {
+	if c.tenant == "" {
+		return expr
+	}
+	rewritten, err := injectTenantMatcher(expr, c.tenant)
+	if err != nil {
+		return expr
+	}
+	return rewritten
+}
+
+// injectTenantMatcher adds a tenant="<value>" matcher to every vector
+// selector in expr, the same way expressions.injectNamespaceMatcher
+// does for namespace isolation.
+func injectTenantMatcher(expr, tenant string) (string, error) {
+	e, err := parser.ParseExpr(expr)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse expression %q: %v", expr, err)
+	}
+
+	tenantMatcher, err := labels.NewMatcher(labels.MatchEqual, "tenant", tenant)
+	if err != nil {
+		return "", err
+	}
+
+	parser.Inspect(e, func(node parser.Node, path []parser.Node) error {
+		if vs, ok := node.(*parser.VectorSelector); ok {
+			vs.LabelMatchers = append(vs.LabelMatchers, tenantMatcher)
+		}
+		return nil
+	})
+
+	return e.String(), nil
+}
+
+// partialResponseRoundTripper appends query.partial-response=false to
+// every outgoing request, the per-query equivalent of the Thanos
+// Querier's --query.partial-response flag.
+type partialResponseRoundTripper struct {
+	base http.RoundTripper
+}
+
+func (rt partialResponseRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := rt.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	q := req.URL.Query()
+	q.Set("query.partial-response", "false")
+	req.URL.RawQuery = q.Encode()
+
+	return base.RoundTrip(req)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package victoriametrics implements monitoring.Interface against
+// VictoriaMetrics' Prometheus-compatible query API. Namespace isolation
+// for ad hoc expressions still goes through
+// expressions.ReplaceNamespaceFns like every other backend, but for the
+// structured named-metric queries this package additionally sets
+// VictoriaMetrics' own extra_label query parameter from
+// QueryOption.NamespaceName, which lets VictoriaMetrics skip
+// irrelevant time series before it even evaluates the expression rather
+// than filtering them out afterwards.
+package victoriametrics
+
+import (
+	"net/http"
+	"time"
+
+	"kubesphere.io/kubesphere/pkg/simple/client/observability/monitoring"
+	"kubesphere.io/kubesphere/pkg/simple/client/observability/monitoring/promapi"
+)
+
+type Client struct {
+	promapi.Client
+	address string
+}
+
+func New(address string) (monitoring.Interface, error) {
+	c, err := promapi.NewClient(address, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{Client: c, address: address}, nil
+}
+
+func (c *Client) Name() string {
+	return monitoring.BackendVictoriaMetrics
+}
+
+func (c *Client) GetNamedMetrics(metrics []string, t time.Time, opt monitoring.QueryOption) []monitoring.Metric {
+	return c.scopedToNamespace(opt).GetNamedMetrics(metrics, t, opt)
+}
+
+func (c *Client) GetNamedMetricsOverTime(metrics []string, start, end time.Time, step time.Duration, opt monitoring.QueryOption) []monitoring.Metric {
+	return c.scopedToNamespace(opt).GetNamedMetricsOverTime(metrics, start, end, step, opt)
+}
+
+// scopedToNamespace returns a client that appends VictoriaMetrics'
+// extra_label=namespace=<value> query parameter to every request it
+// makes, or c.Client unchanged if opt carries no namespace. A fresh
+// client is built per call rather than mutating shared transport state,
+// since QueryOption.NamespaceName differs request to request.
+func (c *Client) scopedToNamespace(opt monitoring.QueryOption) promapi.Client {
+	if opt.NamespaceName == "" {
+		return c.Client
+	}
+	scoped, err := promapi.NewClient(c.address, extraLabelRoundTripper{namespace: opt.NamespaceName})
+	if err != nil {
+		return c.Client
+	}
+	return scoped
+}
+
+// extraLabelRoundTripper appends VictoriaMetrics' extra_label query
+// parameter to every outgoing request.
+type extraLabelRoundTripper struct {
+	namespace string
+}
+
+func (rt extraLabelRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	q := req.URL.Query()
+	q.Add("extra_label", "namespace="+rt.namespace)
+	req.URL.RawQuery = q.Encode()
+	return http.DefaultTransport.RoundTrip(req)
+}
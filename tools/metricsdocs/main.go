@@ -0,0 +1,83 @@
+/*
+Copyright 2022 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command metricsdocs walks the recordingrules/alerts registries and
+// emits a Markdown reference for them, in the spirit of
+// kube-state-metrics' `mksdocs`. Run it and check in the result with:
+//
+//	go run ./tools/metricsdocs > docs/reference/alerting-rules.md
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"kubesphere.io/kubesphere/pkg/models/observability/monitoring/rules/alerts"
+	"kubesphere.io/kubesphere/pkg/models/observability/monitoring/rules/recordingrules"
+)
+
+func main() {
+	out := flag.String("out", "", "file to write the generated Markdown to (defaults to stdout)")
+	flag.Parse()
+
+	w := os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	fmt.Fprintln(w, "# KubeSphere alerting rules reference")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "This file is generated by `go run ./tools/metricsdocs`. Do not edit it by hand.")
+
+	writeRecordingRules(w)
+	writeAlerts(w)
+}
+
+func writeRecordingRules(w *os.File) {
+	rr := recordingrules.List()
+	sort.Slice(rr, func(i, j int) bool { return rr[i].Name < rr[j].Name })
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "## Recording rules")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Name | Expression |")
+	fmt.Fprintln(w, "| --- | --- |")
+	for _, r := range rr {
+		fmt.Fprintf(w, "| `%s` | `%s` |\n", r.Name, r.Expr)
+	}
+}
+
+func writeAlerts(w *os.File) {
+	a := alerts.List()
+	sort.Slice(a, func(i, j int) bool { return a[i].Name < a[j].Name })
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "## Alerts")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Name | Severity | Expression | Runbook |")
+	fmt.Fprintln(w, "| --- | --- | --- | --- |")
+	for _, alert := range a {
+		fmt.Fprintf(w, "| `%s` | %s | `%s` | [link](%s) |\n", alert.Name, alert.Severity, alert.Expr, alert.RunbookURL)
+	}
+}